@@ -0,0 +1,208 @@
+package pending
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// Priority determines how waiters are ordered when StrategyBlock must choose
+// among several goroutines queued for a concurrency slot. Higher values are
+// admitted first; PriorityDefault is used when a task does not specify one.
+type Priority int
+
+// PriorityDefault is the priority assigned to tasks that do not call
+// WithPriority.
+const PriorityDefault Priority = 0
+
+type priorityContextKey struct{}
+
+// WithPriorityKey returns a context carrying priority, mirroring the
+// SchedPriorityKey pattern used by the lotus scheduler. The manager itself
+// never reads this value; it is a plumbing helper for a Task that schedules
+// further work and wants to propagate its own priority to it, via
+// WithPriority on the nested Schedule call.
+func WithPriorityKey(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+// PriorityFromContext returns the priority previously attached with
+// WithPriorityKey, if any.
+func PriorityFromContext(ctx context.Context) (Priority, bool) {
+	p, ok := ctx.Value(priorityContextKey{}).(Priority)
+	return p, ok
+}
+
+// waiter is a single goroutine queued for a concurrency slot.
+type waiter struct {
+	priority Priority
+	ready    chan struct{}
+	index    int // maintained by waiterHeap; unused in bucketed mode
+}
+
+// admission is a priority-aware replacement for a plain chan struct{}
+// semaphore. Waiters register under mu and are woken in priority order
+// (highest first) as slots are released.
+type admission struct {
+	mu       sync.Mutex
+	capacity int
+	inUse    int
+
+	levels  int // 0 selects the heap-backed queue; >0 selects bucketed queues
+	heap    waiterHeap
+	buckets [][]*waiter
+}
+
+func newAdmission(capacity, levels int) *admission {
+	a := &admission{capacity: capacity, levels: levels}
+	if levels > 0 {
+		a.buckets = make([][]*waiter, levels)
+	}
+	return a
+}
+
+// tryAcquire claims a slot without blocking, for StrategyDrop.
+func (a *admission) tryAcquire() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.inUse < a.capacity {
+		a.inUse++
+		return true
+	}
+	return false
+}
+
+// acquire blocks until a slot is available or ctx is done. priority
+// determines how soon this waiter is woken relative to others queued.
+func (a *admission) acquire(ctx context.Context, priority Priority) bool {
+	a.mu.Lock()
+	if a.inUse < a.capacity {
+		a.inUse++
+		a.mu.Unlock()
+		return true
+	}
+
+	w := &waiter{priority: priority, ready: make(chan struct{})}
+	a.enqueue(w)
+	a.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return true
+	case <-ctx.Done():
+		a.mu.Lock()
+		removed := a.dequeueWaiter(w)
+		a.mu.Unlock()
+		if !removed {
+			// A concurrent release already granted us the slot; honor the
+			// grant and give it straight back so it isn't leaked.
+			<-w.ready
+			a.release()
+		}
+		return false
+	}
+}
+
+// release returns a slot to the pool, waking the highest-priority waiter if
+// one is queued.
+func (a *admission) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if w := a.dequeueHighest(); w != nil {
+		close(w.ready)
+		return
+	}
+	a.inUse--
+}
+
+func (a *admission) enqueue(w *waiter) {
+	if a.levels > 0 {
+		b := a.bucketOf(w.priority)
+		a.buckets[b] = append(a.buckets[b], w)
+		return
+	}
+	heap.Push(&a.heap, w)
+}
+
+func (a *admission) dequeueHighest() *waiter {
+	if a.levels > 0 {
+		for b := a.levels - 1; b >= 0; b-- {
+			if len(a.buckets[b]) > 0 {
+				w := a.buckets[b][0]
+				a.buckets[b] = a.buckets[b][1:]
+				return w
+			}
+		}
+		return nil
+	}
+
+	if a.heap.Len() == 0 {
+		return nil
+	}
+	return heap.Pop(&a.heap).(*waiter)
+}
+
+func (a *admission) dequeueWaiter(target *waiter) bool {
+	if a.levels > 0 {
+		b := a.bucketOf(target.priority)
+		q := a.buckets[b]
+		for i, w := range q {
+			if w == target {
+				a.buckets[b] = append(q[:i], q[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+
+	for i, w := range a.heap {
+		if w == target {
+			heap.Remove(&a.heap, i)
+			return true
+		}
+	}
+	return false
+}
+
+// bucketOf maps a priority onto [0, levels), clamping out-of-range values to
+// the lowest/highest bucket.
+func (a *admission) bucketOf(p Priority) int {
+	b := int(p)
+	if b < 0 {
+		b = 0
+	}
+	if b >= a.levels {
+		b = a.levels - 1
+	}
+	return b
+}
+
+// waiterHeap orders waiters by descending priority (highest priority at the
+// top) for container/heap.
+type waiterHeap []*waiter
+
+func (h waiterHeap) Len() int           { return len(h) }
+func (h waiterHeap) Less(i, j int) bool { return h[i].priority > h[j].priority }
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waiterHeap) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waiterHeap) Pop() any {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*h = old[:n-1]
+	return w
+}
@@ -0,0 +1,35 @@
+package pending
+
+import "time"
+
+// Timer is the handle returned by Clock.AfterFunc. *time.Timer already
+// satisfies this interface.
+type Timer interface {
+	// Stop prevents the Timer from firing, as *time.Timer.Stop.
+	Stop() bool
+	// Reset reschedules the Timer to fire after d, as *time.Timer.Reset.
+	Reset(d time.Duration) bool
+}
+
+// Clock abstracts time so the manager's scheduling, retry backoff, and
+// recurrence bookkeeping can be driven deterministically in tests, following
+// the gvisor tcpip.Clock/Job split. See the pendingtest subpackage for a
+// ManualClock that fires due timers synchronously on Advance instead of
+// waiting on the wall clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc waits for d to elapse and then calls f, as time.AfterFunc.
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// RealClock is the default Clock, backed by the time package.
+type RealClock struct{}
+
+// Now returns time.Now().
+func (RealClock) Now() time.Time { return time.Now() }
+
+// AfterFunc delegates to time.AfterFunc.
+func (RealClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
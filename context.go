@@ -0,0 +1,42 @@
+package pending
+
+import (
+	"context"
+	"time"
+)
+
+type telemetryContextKey struct{}
+
+// WithTaskLogger returns the TelemetryHandler attached to ctx for the
+// current task attempt, decorated so its calls don't need the task's id
+// (and, for OnRetried, attempt number) threaded through manually. Returns
+// the no-op handler if ctx was not derived from a Task invocation.
+func WithTaskLogger(ctx context.Context) TelemetryHandler {
+	if l, ok := ctx.Value(telemetryContextKey{}).(taskLogger); ok {
+		return l
+	}
+	return nopLogger{}
+}
+
+func withTaskLogger(ctx context.Context, underlying TelemetryHandler, id string, attempt int) context.Context {
+	return context.WithValue(ctx, telemetryContextKey{}, taskLogger{underlying: underlying, id: id, attempt: attempt})
+}
+
+// taskLogger decorates a TelemetryHandler with a fixed task id and attempt
+// number, so a Task retrieved via WithTaskLogger can emit events through the
+// manager's telemetry sink without repeating them on every call.
+type taskLogger struct {
+	underlying TelemetryHandler
+	id         string
+	attempt    int
+}
+
+func (l taskLogger) OnScheduled(_ string, d time.Duration) { l.underlying.OnScheduled(l.id, d) }
+func (l taskLogger) OnRescheduled(_ string)                { l.underlying.OnRescheduled(l.id) }
+func (l taskLogger) OnExecuted(_ string, d time.Duration)  { l.underlying.OnExecuted(l.id, d) }
+func (l taskLogger) OnCancelled(_ string)                  { l.underlying.OnCancelled(l.id) }
+func (l taskLogger) OnFailed(_ string, err error)          { l.underlying.OnFailed(l.id, err) }
+func (l taskLogger) OnRetried(_ string, _ int)             { l.underlying.OnRetried(l.id, l.attempt) }
+func (l taskLogger) OnRecurrenceSkipped(_ string, reason string) {
+	l.underlying.OnRecurrenceSkipped(l.id, reason)
+}
@@ -2,6 +2,15 @@
 //
 // Tasks are keyed by ID, so scheduling with the same ID replaces the previous
 // task (debouncing). The manager supports cancellation, graceful shutdown, and
-// optional concurrency limits with block or drop strategies.
+// optional concurrency limits with block or drop strategies. Individual tasks
+// may declare a per-attempt timeout/deadline and a retry policy via TaskOption.
+// ScheduleRecurring re-arms a task on a RecurrencePolicy (EveryPolicy,
+// DailyAtPolicy, WeeklyPolicy) after each run. All scheduling, retry backoff,
+// and recurrence timing go through a Clock, which defaults to RealClock but
+// can be swapped via WithClock for deterministic tests (see the pendingtest
+// subpackage). WithBaseContext seeds each task's context with caller-supplied
+// values, and WithTaskLogger retrieves a per-task TelemetryHandler from that
+// same context. Pending and Running report a live snapshot of scheduled and
+// executing tasks, and Stats exposes lock-free counters that WithMetricsRegisterer
+// can plug into a Prometheus-style collector.
 package pending
-
@@ -15,6 +15,8 @@ func (exampleTelemetry) OnCancelled(id string)                        {}
 func (exampleTelemetry) OnFailed(id string, err error) {
 	_ = errors.Is(err, ErrTaskDropped)
 }
+func (exampleTelemetry) OnRetried(id string, attempt int)      {}
+func (exampleTelemetry) OnRecurrenceSkipped(id, reason string) {}
 
 func ExampleNewManager() {
 	mgr := NewManager(
@@ -23,5 +25,5 @@ func ExampleNewManager() {
 	)
 	defer mgr.Shutdown(context.Background())
 
-	mgr.Schedule("email:user-42", 2*time.Second, func(ctx context.Context) {})
+	mgr.Schedule("email:user-42", 2*time.Second, func(ctx context.Context) error { return nil })
 }
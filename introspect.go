@@ -0,0 +1,159 @@
+package pending
+
+import "time"
+
+// TaskState describes where a task currently sits in its lifecycle, as
+// reported by Pending and Running.
+type TaskState int
+
+const (
+	// StatePending means the task's timer has been armed but has not fired.
+	StatePending TaskState = iota
+	// StateWaitingForSlot means the task's timer fired but it is queued for
+	// a concurrency slot under WithLimit. Without a limit a task passes
+	// through this state only momentarily on its way to StateRunning.
+	StateWaitingForSlot
+	// StateRunning means the task is currently executing.
+	StateRunning
+)
+
+// stateRemoved marks an entry whose running-gauge contribution has already
+// been settled, so whichever of markDone or untrackRemoved observes
+// StateRunning first is the only one that decrements runningGauge. It is
+// purely internal bookkeeping: snapshot skips entries in this state, so a
+// removed entry is never visible to Pending or Running.
+const stateRemoved TaskState = -1
+
+// TaskInfo is a read-only snapshot of a single pending or running task, as
+// returned by Pending and Running.
+type TaskInfo struct {
+	ID          string
+	ScheduledAt time.Time
+	FireAt      time.Time
+	Attempt     int
+	Priority    Priority
+	State       TaskState
+}
+
+// ManagerStats summarizes a Manager's activity. PendingCount and
+// RunningCount reflect the current moment; the *Total fields and AvgLatency
+// accumulate over the Manager's lifetime.
+type ManagerStats struct {
+	PendingCount  int
+	RunningCount  int
+	DroppedTotal  int64
+	ExecutedTotal int64
+	RetriedTotal  int64
+	AvgLatency    time.Duration
+}
+
+// Pending returns a snapshot of every task that has not started executing,
+// including those currently queued for a concurrency slot.
+func (m *Manager) Pending() []TaskInfo {
+	return m.snapshot(func(s TaskState) bool { return s != StateRunning })
+}
+
+// Running returns a snapshot of every task currently executing.
+func (m *Manager) Running() []TaskInfo {
+	return m.snapshot(func(s TaskState) bool { return s == StateRunning })
+}
+
+func (m *Manager) snapshot(include func(TaskState) bool) []TaskInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]TaskInfo, 0, len(m.pending))
+	for id, e := range m.pending {
+		state := TaskState(e.state.Load())
+		if state == stateRemoved || !include(state) {
+			continue
+		}
+		infos = append(infos, TaskInfo{
+			ID:          id,
+			ScheduledAt: e.scheduledAt,
+			FireAt:      e.fireAt,
+			Attempt:     int(e.attempt.Load()),
+			Priority:    e.opts.Priority,
+			State:       state,
+		})
+	}
+	return infos
+}
+
+// Stats returns a snapshot of the manager's counters. It only reads atomic
+// values, so it never blocks on m.mu regardless of how many tasks are
+// pending or running.
+func (m *Manager) Stats() ManagerStats {
+	executed := m.executedTotal.Load()
+	var avg time.Duration
+	if executed > 0 {
+		avg = time.Duration(m.executedNanosTotal.Load() / executed)
+	}
+	return ManagerStats{
+		PendingCount:  int(m.pendingGauge.Load()),
+		RunningCount:  int(m.runningGauge.Load()),
+		DroppedTotal:  m.droppedTotal.Load(),
+		ExecutedTotal: executed,
+		RetriedTotal:  m.retriedTotal.Load(),
+		AvgLatency:    avg,
+	}
+}
+
+// markPending records e as freshly armed: its timer is set but has not yet
+// fired. Called with m.mu held, from scheduleEntry, scheduleRetry, and rearm.
+func (m *Manager) markPending(e *entry, attempt int, scheduledAt, fireAt time.Time) {
+	e.attempt.Store(int32(attempt))
+	e.scheduledAt = scheduledAt
+	e.fireAt = fireAt
+	e.state.Store(int32(StatePending))
+	m.pendingGauge.Add(1)
+}
+
+// markWaiting records that e's timer has fired and it is now queuing for a
+// concurrency slot (or, without a limit, about to run immediately). Guarded
+// on the same sentinel as markDone/untrackRemoved: if e was already removed
+// (Cancel/Shutdown/replace raced in ahead of this call), the CAS fails and
+// the removed marker is left alone.
+func (m *Manager) markWaiting(e *entry) {
+	e.state.CompareAndSwap(int32(StatePending), int32(StateWaitingForSlot))
+}
+
+// markRunning records that e has acquired a slot and is now executing. Like
+// markWaiting, it only takes effect if e is still in the state markWaiting
+// left it in; if it was removed in the meantime, the gauges are left alone
+// entirely, since untrackRemoved has already (or will) account for them.
+func (m *Manager) markRunning(e *entry) {
+	if !e.state.CompareAndSwap(int32(StateWaitingForSlot), int32(StateRunning)) {
+		return
+	}
+	m.pendingGauge.Add(-1)
+	m.runningGauge.Add(1)
+}
+
+// markDone records that e's task call has returned, whatever the outcome. If
+// untrackRemoved already claimed this running stint (the task was cancelled
+// out from under it), markDone is a no-op, so runningGauge is decremented
+// exactly once.
+func (m *Manager) markDone(e *entry) {
+	if e.state.Swap(int32(stateRemoved)) == int32(StateRunning) {
+		m.runningGauge.Add(-1)
+	}
+}
+
+// untrackRemoved settles e's gauge contribution when it is removed from the
+// pending set (cancelled, replaced, or dropped), so Stats stays consistent
+// with Pending/Running even when that happens mid-execution: if e was
+// running, runningGauge is decremented immediately (matching Running()
+// ceasing to report it right away) rather than waiting for markDone, which
+// becomes a no-op once it does run. Otherwise pendingGauge is decremented as
+// before.
+func (m *Manager) untrackRemoved(e *entry) {
+	switch TaskState(e.state.Swap(int32(stateRemoved))) {
+	case StateRunning:
+		m.runningGauge.Add(-1)
+	case stateRemoved:
+		// Already settled by a concurrent markDone or untrackRemoved call.
+	default:
+		m.pendingGauge.Add(-1)
+	}
+}
@@ -1,5 +1,7 @@
 package pending
 
+import "context"
+
 // Strategy defines how the manager behaves when the concurrency limit is reached.
 type Strategy int
 
@@ -12,16 +14,31 @@ const (
 
 type Option func(*Manager)
 
-// WithLimit sets the maximum number of concurrent tasks.
+// WithLimit sets the maximum number of concurrent tasks. Under StrategyBlock,
+// waiters are admitted in priority order; see WithPriority and
+// WithPriorityLevels.
 func WithLimit(limit int, strategy Strategy) Option {
 	return func(m *Manager) {
 		if limit > 0 {
-			m.semaphore = make(chan struct{}, limit)
+			m.limit = limit
 			m.strategy = strategy
 		}
 	}
 }
 
+// WithPriorityLevels buckets task priorities into n FIFO queues so the
+// admission structure can select the highest-priority waiter in time
+// independent of the number of waiters, rather than via a priority heap.
+// Priorities outside [0, n) are clamped to the nearest bucket. Only takes
+// effect alongside WithLimit.
+func WithPriorityLevels(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.priorityLevels = n
+		}
+	}
+}
+
 // WithLogger attaches a custom TelemetryHandler.
 func WithLogger(logger TelemetryHandler) Option {
 	return func(m *Manager) {
@@ -30,3 +47,67 @@ func WithLogger(logger TelemetryHandler) Option {
 		}
 	}
 }
+
+// WithErrorHandler registers a callback invoked once a task's retries
+// (if any) are exhausted without success. attempt is the number of the
+// final, failed attempt.
+func WithErrorHandler(handler func(id string, err error, attempt int)) Option {
+	return func(m *Manager) {
+		m.errorHandler = handler
+	}
+}
+
+// WithClock overrides the time source used for scheduling, retry backoff,
+// and recurrence. Tests can supply a pendingtest.ManualClock to drive the
+// whole pipeline deterministically via Advance, instead of time.Sleep.
+func WithClock(clock Clock) Option {
+	return func(m *Manager) {
+		if clock != nil {
+			m.clock = clock
+		}
+	}
+}
+
+// MetricsRegisterer lets a Prometheus-style collector observe a Manager's
+// counters without implementing the full TelemetryHandler interface. Each
+// Register call is made once, during NewManager, with a value func that
+// reads the live counter; the collector is expected to call it on its own
+// scrape/collect cycle.
+type MetricsRegisterer interface {
+	// RegisterGauge registers a callback reporting a point-in-time value,
+	// such as PendingCount or RunningCount.
+	RegisterGauge(name string, value func() float64)
+	// RegisterCounter registers a callback reporting a monotonically
+	// increasing value, such as ExecutedTotal.
+	RegisterCounter(name string, value func() float64)
+}
+
+// WithMetricsRegisterer plugs r into the manager's counters so they can be
+// exported alongside the rest of an application's metrics, without requiring
+// callers to implement TelemetryHandler just to count things.
+func WithMetricsRegisterer(r MetricsRegisterer) Option {
+	return func(m *Manager) {
+		if r == nil {
+			return
+		}
+		r.RegisterGauge("pending_tasks", func() float64 { return float64(m.pendingGauge.Load()) })
+		r.RegisterGauge("running_tasks", func() float64 { return float64(m.runningGauge.Load()) })
+		r.RegisterCounter("dropped_tasks_total", func() float64 { return float64(m.droppedTotal.Load()) })
+		r.RegisterCounter("executed_tasks_total", func() float64 { return float64(m.executedTotal.Load()) })
+		r.RegisterCounter("retried_tasks_total", func() float64 { return float64(m.retriedTotal.Load()) })
+	}
+}
+
+// WithBaseContext sets the function used to seed each task's context, as in
+// asynq's baseCtxFn. Use it to carry request-scoped values (tenant id, a
+// tracing span, a structured logger) into every Task. The manager still
+// wraps the returned context with context.WithCancel for Cancel/Shutdown, so
+// a Task's context is cancelled when either the manager or fn's context is
+// done.
+func WithBaseContext(fn func() context.Context) Option {
+	return func(m *Manager) {
+		if fn != nil {
+			m.baseCtxFn = fn
+		}
+	}
+}
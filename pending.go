@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,8 +13,69 @@ import (
 var ErrTaskDropped = errors.New("pending: task dropped due to concurrency limit")
 
 // Task defines the function signature for a scheduled action.
-// The provided context is cancelled if the manager shuts down or the task is replaced.
-type Task func(ctx context.Context)
+// The provided context is cancelled if the manager shuts down or the task is
+// replaced, and carries a deadline when Timeout or Deadline is set via
+// TaskOption. A non-nil return schedules a retry, up to MaxRetries.
+type Task func(ctx context.Context) error
+
+// RetryDelayFunc computes the backoff before retrying a failed task. attempt
+// is the number of the attempt that just failed (1 for the initial attempt).
+type RetryDelayFunc func(attempt int, err error) time.Duration
+
+// ScheduleOptions controls per-task execution bounds and retry behavior.
+// It is populated by the TaskOption values passed to Schedule.
+type ScheduleOptions struct {
+	// Timeout bounds how long a single attempt may run. Ignored if Deadline is set.
+	Timeout time.Duration
+	// Deadline bounds how long a single attempt may run. Takes precedence over Timeout.
+	Deadline time.Time
+	// MaxRetries is the number of additional attempts made after a failure.
+	MaxRetries int
+	// RetryDelayFunc computes the backoff before each retry. If nil, retries fire immediately.
+	RetryDelayFunc RetryDelayFunc
+	// Priority determines how soon this task is admitted relative to other
+	// waiters when StrategyBlock must queue for a concurrency slot.
+	Priority Priority
+	// StopRecurrenceOnFailure ends a ScheduleRecurring series instead of
+	// re-arming it when a run fails and its retries (if any) are exhausted.
+	StopRecurrenceOnFailure bool
+}
+
+// TaskOption configures a single Schedule call.
+type TaskOption func(*ScheduleOptions)
+
+// WithTimeout bounds each attempt of the task to duration d.
+func WithTimeout(d time.Duration) TaskOption {
+	return func(o *ScheduleOptions) { o.Timeout = d }
+}
+
+// WithDeadline bounds each attempt of the task to the fixed point in time t.
+func WithDeadline(t time.Time) TaskOption {
+	return func(o *ScheduleOptions) { o.Deadline = t }
+}
+
+// WithMaxRetries sets the number of additional attempts made after a failure.
+func WithMaxRetries(n int) TaskOption {
+	return func(o *ScheduleOptions) { o.MaxRetries = n }
+}
+
+// WithRetryDelayFunc sets the backoff computation used between retries.
+func WithRetryDelayFunc(f RetryDelayFunc) TaskOption {
+	return func(o *ScheduleOptions) { o.RetryDelayFunc = f }
+}
+
+// WithPriority sets the task's priority for admission under StrategyBlock.
+// Higher values are admitted before lower ones when waiters are queued.
+func WithPriority(p Priority) TaskOption {
+	return func(o *ScheduleOptions) { o.Priority = p }
+}
+
+// WithStopRecurrenceOnFailure ends a ScheduleRecurring series when a run
+// fails and its retries (if any) are exhausted, instead of the default of
+// re-arming for the next tick regardless of outcome.
+func WithStopRecurrenceOnFailure() TaskOption {
+	return func(o *ScheduleOptions) { o.StopRecurrenceOnFailure = true }
+}
 
 // Manager coordinates the lifecycle of delayed tasks, ensuring thread-safety
 // and providing concurrency control via semaphores.
@@ -21,19 +83,48 @@ type Manager struct {
 	mu      sync.RWMutex
 	pending map[string]*entry
 
-	semaphore chan struct{}
-	strategy  Strategy
-	logger    TelemetryHandler
+	admission      *admission
+	limit          int
+	priorityLevels int
+	strategy       Strategy
+	logger         TelemetryHandler
+	errorHandler   func(id string, err error, attempt int)
+	clock          Clock
+	baseCtxFn      func() context.Context
 
 	wg           sync.WaitGroup
 	isClosed     bool
 	shutdownOnce sync.Once
 	shutdownDone chan struct{}
+
+	// Counters backing Stats. The *Total counters are updated at the same
+	// call sites that report to logger; the gauges are updated by markPending,
+	// markRunning, markDone and untrackRemoved. All are atomic, so Stats can
+	// read them without locking.
+	pendingGauge       atomic.Int64
+	runningGauge       atomic.Int64
+	droppedTotal       atomic.Int64
+	executedTotal      atomic.Int64
+	executedNanosTotal atomic.Int64
+	retriedTotal       atomic.Int64
 }
 
 type entry struct {
-	timer  *time.Timer
-	cancel context.CancelFunc
+	timer      Timer
+	ctx        context.Context
+	cancel     context.CancelFunc
+	opts       ScheduleOptions
+	recurrence RecurrencePolicy // nil unless scheduled via ScheduleRecurring
+	nextTick   time.Time        // the tick due after the one about to fire; used to detect overrun
+
+	// Bookkeeping for Pending/Running/Stats. scheduledAt and fireAt are only
+	// written while m.mu is held (scheduleEntry/scheduleRetry/rearm); state
+	// and attempt are written outside m.mu by a running attempt, so they are
+	// atomic.
+	scheduledAt time.Time
+	fireAt      time.Time
+	attempt     atomic.Int32
+	state       atomic.Int32 // TaskState
 }
 
 // NewManager initializes a new Manager with the provided options.
@@ -41,18 +132,58 @@ func NewManager(opts ...Option) *Manager {
 	m := &Manager{
 		pending:      make(map[string]*entry),
 		logger:       nopLogger{},
+		clock:        RealClock{},
+		baseCtxFn:    context.Background,
 		shutdownDone: make(chan struct{}),
 	}
 	for _, opt := range opts {
 		opt(m)
 	}
+	if m.limit > 0 {
+		m.admission = newAdmission(m.limit, m.priorityLevels)
+	}
 	return m
 }
 
 // Schedule plans a task for execution after duration d.
 // If a task with the same id already exists, the previous one is cancelled
 // and replaced (debouncing). If the manager is closed, Schedule does nothing.
-func (m *Manager) Schedule(id string, d time.Duration, task Task) {
+// opts may supply a timeout/deadline for each attempt and a retry policy.
+func (m *Manager) Schedule(id string, d time.Duration, task Task, opts ...TaskOption) {
+	var o ScheduleOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	m.scheduleEntry(id, d, o, nil, task, time.Time{})
+}
+
+// ScheduleRecurring plans a task to run repeatedly according to policy.
+// After each run, policy.Next(clock.Now()), using the manager's Clock,
+// determines the next tick and the
+// same id is re-armed, so Cancel(id) stops the whole series. By default the
+// series continues after a failed run (once its retries, if any, are
+// exhausted); pass WithStopRecurrenceOnFailure to end it instead. Scheduling
+// a new task (recurring or not) under the same id replaces the series
+// (debouncing), same as Schedule.
+func (m *Manager) ScheduleRecurring(id string, policy RecurrencePolicy, task Task, opts ...TaskOption) {
+	var o ScheduleOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	now := m.clock.Now()
+	tick := policy.Next(now)
+	delay := tick.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	m.scheduleEntry(id, delay, o, policy, task, tick)
+}
+
+// scheduleEntry arms id to fire after d. fireAt is the instant the task is
+// due (only meaningful when recurrence is non-nil, to seed nextTick for
+// overrun detection).
+func (m *Manager) scheduleEntry(id string, d time.Duration, opts ScheduleOptions, recurrence RecurrencePolicy, task Task, fireAt time.Time) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -64,78 +195,206 @@ func (m *Manager) Schedule(id string, d time.Duration, task Task) {
 	if old, exists := m.pending[id]; exists {
 		old.timer.Stop()
 		old.cancel()
+		m.untrackRemoved(old)
 		m.logger.OnRescheduled(id)
 	} else {
 		m.logger.OnScheduled(id, d)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	e := &entry{cancel: cancel}
+	ctx, cancel := context.WithCancel(m.baseCtxFn())
+	e := &entry{ctx: ctx, cancel: cancel, opts: opts, recurrence: recurrence}
+	if recurrence != nil {
+		e.nextTick = recurrence.Next(fireAt)
+	}
+
+	now := m.clock.Now()
+	m.markPending(e, 1, now, now.Add(d))
 
 	// Schedule the execution.
-	e.timer = time.AfterFunc(d, func() {
-		// Hold a read lock so Shutdown cannot reach wg.Wait before wg.Go is called.
-		m.mu.RLock()
-		defer m.mu.RUnlock()
-
-		if m.isClosed {
-			cancel()
-			return
+	e.timer = m.clock.AfterFunc(d, func() {
+		m.fire(id, e, task, 1)
+	})
+
+	m.pending[id] = e
+}
+
+// fire hands the given attempt off to the worker pool, guarding against a
+// race with Shutdown reaching wg.Wait before wg.Go is called.
+func (m *Manager) fire(id string, e *entry, task Task, attempt int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.isClosed {
+		e.cancel()
+		return
+	}
+
+	m.wg.Go(func() {
+		m.runAttempt(id, e, task, attempt)
+	})
+}
+
+func (m *Manager) runAttempt(id string, e *entry, task Task, attempt int) {
+	m.markWaiting(e)
+	if !m.acquireSlot(e.ctx, id, e) {
+		return
+	}
+	m.markRunning(e)
+	defer m.releaseSlot()
+
+	taskCtx, taskCancel := withAttemptBounds(e.ctx, e.opts)
+	defer taskCancel()
+	taskCtx = WithPriorityKey(taskCtx, e.opts.Priority)
+	taskCtx = withTaskLogger(taskCtx, m.logger, id, attempt)
+
+	start := m.clock.Now()
+	err := task(taskCtx)
+	m.markDone(e)
+
+	if err == nil {
+		m.completeSeries(id, e, task, true)
+		dur := m.clock.Now().Sub(start)
+		m.logger.OnExecuted(id, dur)
+		m.executedTotal.Add(1)
+		m.executedNanosTotal.Add(int64(dur))
+		return
+	}
+
+	if errors.Is(err, context.Canceled) {
+		// Cancelled via Cancel/Shutdown/replacement or the base context; not a failure.
+		return
+	}
+
+	m.logger.OnFailed(id, err)
+
+	if attempt > e.opts.MaxRetries {
+		m.completeSeries(id, e, task, false)
+		if m.errorHandler != nil {
+			m.errorHandler(id, err, attempt)
 		}
+		return
+	}
 
-		m.wg.Go(func() {
-			defer cancel()
+	m.scheduleRetry(id, e, task, attempt, err)
+}
 
-			if !m.acquireSlot(ctx, id, e) {
-				return
-			}
-			defer m.releaseSlot()
+// withAttemptBounds derives a context bounded by opts.Deadline or opts.Timeout,
+// whichever is set. Deadline takes precedence when both are set.
+func withAttemptBounds(parent context.Context, opts ScheduleOptions) (context.Context, context.CancelFunc) {
+	if !opts.Deadline.IsZero() {
+		return context.WithDeadline(parent, opts.Deadline)
+	}
+	if opts.Timeout > 0 {
+		return context.WithTimeout(parent, opts.Timeout)
+	}
+	return parent, func() {}
+}
 
-			start := time.Now()
-			task(ctx)
+// scheduleRetry re-arms entry e to run the next attempt after the configured
+// backoff, as long as e is still the current entry for id.
+func (m *Manager) scheduleRetry(id string, e *entry, task Task, failedAttempt int, err error) {
+	var delay time.Duration
+	if e.opts.RetryDelayFunc != nil {
+		delay = e.opts.RetryDelayFunc(failedAttempt, err)
+	}
+	nextAttempt := failedAttempt + 1
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.isClosed {
+		return
+	}
+	if current, ok := m.pending[id]; !ok || current != e {
+		return
+	}
+
+	m.logger.OnRetried(id, nextAttempt)
+	m.retriedTotal.Add(1)
 
-			m.deleteIfCurrent(id, e)
-			m.logger.OnExecuted(id, time.Since(start))
-		})
+	now := m.clock.Now()
+	m.markPending(e, nextAttempt, now, now.Add(delay))
+	e.timer = m.clock.AfterFunc(delay, func() {
+		m.fire(id, e, task, nextAttempt)
 	})
+}
 
-	m.pending[id] = e
+// completeSeries finishes a one-shot entry, or re-arms a recurring one for
+// its next tick. A failed, retries-exhausted run only re-arms the series if
+// StopRecurrenceOnFailure was not set.
+func (m *Manager) completeSeries(id string, e *entry, task Task, succeeded bool) {
+	if e.recurrence != nil && (succeeded || !e.opts.StopRecurrenceOnFailure) {
+		m.rearm(id, e, task)
+		return
+	}
+	m.finish(id, e)
+}
+
+// rearm schedules the next occurrence of a recurring entry. If the run that
+// just completed took long enough to reach or pass the tick that would
+// naturally have followed it, that tick is reported as skipped via
+// OnRecurrenceSkipped rather than queued up to run immediately.
+func (m *Manager) rearm(id string, e *entry, task Task) {
+	now := m.clock.Now()
+	if !e.nextTick.IsZero() && !e.nextTick.After(now) {
+		m.logger.OnRecurrenceSkipped(id, "previous execution overran the next scheduled tick")
+	}
+
+	tick := e.recurrence.Next(now)
+	delay := tick.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.isClosed {
+		return
+	}
+	if current, ok := m.pending[id]; !ok || current != e {
+		return
+	}
+
+	e.nextTick = e.recurrence.Next(tick)
+	m.markPending(e, 1, now, tick)
+	e.timer = m.clock.AfterFunc(delay, func() {
+		m.fire(id, e, task, 1)
+	})
 }
 
 func (m *Manager) acquireSlot(ctx context.Context, id string, e *entry) bool {
-	if m.semaphore == nil {
+	if m.admission == nil {
 		return true
 	}
 
 	if m.strategy == StrategyDrop {
-		select {
-		case m.semaphore <- struct{}{}:
+		if m.admission.tryAcquire() {
 			return true
-		default:
-			m.logger.OnFailed(id, ErrTaskDropped)
-			m.deleteIfCurrent(id, e)
-			return false
 		}
+		m.logger.OnFailed(id, ErrTaskDropped)
+		m.droppedTotal.Add(1)
+		m.finish(id, e)
+		return false
 	}
 
-	select {
-	case m.semaphore <- struct{}{}:
+	if m.admission.acquire(ctx, e.opts.Priority) {
 		return true
-	case <-ctx.Done():
-		// The task was canceled while waiting for capacity (Cancel/Shutdown/reschedule).
-		m.deleteIfCurrent(id, e)
-		return false
 	}
+	// The task was canceled while waiting for capacity (Cancel/Shutdown/reschedule).
+	m.finish(id, e)
+	return false
 }
 
 func (m *Manager) releaseSlot() {
-	if m.semaphore != nil {
-		<-m.semaphore
+	if m.admission != nil {
+		m.admission.release()
 	}
 }
 
 // Cancel immediately stops a pending task by its ID and prevents it from running.
-// If the task is already running, its context is cancelled.
+// If the task is already running, its context is cancelled. This also stops
+// any retry backoff in progress for the task.
 func (m *Manager) Cancel(id string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -143,11 +402,20 @@ func (m *Manager) Cancel(id string) {
 	if e, ok := m.pending[id]; ok {
 		e.timer.Stop()
 		e.cancel()
+		m.untrackRemoved(e)
 		delete(m.pending, id)
 		m.logger.OnCancelled(id)
 	}
 }
 
+// finish removes e from the pending set (if still current) and releases its
+// context. Safe to call even if a retry was never scheduled.
+func (m *Manager) finish(id string, e *entry) {
+	m.untrackRemoved(e)
+	m.deleteIfCurrent(id, e)
+	e.cancel()
+}
+
 func (m *Manager) deleteIfCurrent(id string, target *entry) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -159,6 +427,7 @@ func (m *Manager) deleteIfCurrent(id string, target *entry) {
 
 // Shutdown stops the manager, cancels all pending timers, and waits for
 // currently executing tasks to complete or for the context to time out.
+// In-flight retries are not re-armed.
 func (m *Manager) Shutdown(ctx context.Context) error {
 	m.shutdownOnce.Do(func() {
 		m.mu.Lock()
@@ -167,6 +436,7 @@ func (m *Manager) Shutdown(ctx context.Context) error {
 		for id, e := range m.pending {
 			e.timer.Stop()
 			e.cancel()
+			m.untrackRemoved(e)
 			delete(m.pending, id)
 			m.logger.OnCancelled(id)
 		}
@@ -7,7 +7,7 @@ import (
 	"time"
 )
 
-var benchmarkTask = func(ctx context.Context) {}
+var benchmarkTask = func(ctx context.Context) error { return nil }
 
 func BenchmarkManager_Schedule(b *testing.B) {
 	mgr := NewManager()
@@ -79,9 +79,10 @@ func BenchmarkManager_Shutdown_WithRunningTasks(b *testing.B) {
 
 		for j := 0; j < runningTasks; j++ {
 			id := strconv.Itoa(j)
-			mgr.Schedule(id, 0, func(ctx context.Context) {
+			mgr.Schedule(id, 0, func(ctx context.Context) error {
 				started <- struct{}{}
 				<-ctx.Done()
+				return nil
 			})
 		}
 
@@ -2,22 +2,55 @@ package pending
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
 	"time"
 )
 
+// waitForWaiters polls until n goroutines are queued in mgr's admission
+// waiting room, or fails the test if that doesn't happen in time. Scheduling
+// a task only starts the goroutine that calls admission.acquire; a fixed
+// sleep can't reliably outlast scheduler jitter (especially under -race), so
+// tests that depend on a waiter actually being enqueued poll for it instead.
+func waitForWaiters(t *testing.T, mgr *Manager, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mgr.admission.mu.Lock()
+		count := mgr.admission.heap.Len()
+		for _, b := range mgr.admission.buckets {
+			count += len(b)
+		}
+		mgr.admission.mu.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d queued waiter(s)", n)
+}
+
 type spyLogger struct {
 	nopLogger
-	mu      sync.Mutex
-	dropped bool
+	mu                sync.Mutex
+	dropped           bool
+	recurrenceSkipped bool
+	failedIDs         []string
 }
 
 func (s *spyLogger) OnFailed(id string, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.dropped = true
+	s.failedIDs = append(s.failedIDs, id)
+}
+
+func (s *spyLogger) OnRecurrenceSkipped(id, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recurrenceSkipped = true
 }
 
 func TestManager_StrategyDrop(t *testing.T) {
@@ -27,14 +60,15 @@ func TestManager_StrategyDrop(t *testing.T) {
 	running := make(chan struct{})
 	release := make(chan struct{})
 
-	mgr.Schedule("t1", 1*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("t1", 1*time.Millisecond, func(ctx context.Context) error {
 		close(running)
 		<-release
+		return nil
 	})
 
 	<-running
 
-	mgr.Schedule("t2", 1*time.Millisecond, func(ctx context.Context) {})
+	mgr.Schedule("t2", 1*time.Millisecond, func(ctx context.Context) error { return nil })
 
 	deadline := time.After(200 * time.Millisecond)
 	for {
@@ -58,7 +92,7 @@ func TestManager_StrategyDrop(t *testing.T) {
 
 func TestManager_Shutdown(t *testing.T) {
 	mgr := NewManager()
-	mgr.Schedule("slow", 1*time.Hour, func(ctx context.Context) {})
+	mgr.Schedule("slow", 1*time.Hour, func(ctx context.Context) error { return nil })
 
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
@@ -74,15 +108,17 @@ func TestManager_StrategyBlock(t *testing.T) {
 	start := make(chan struct{})
 	done := make(chan struct{})
 
-	mgr.Schedule("t1", 1*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("t1", 1*time.Millisecond, func(ctx context.Context) error {
 		close(start)
 		time.Sleep(50 * time.Millisecond)
+		return nil
 	})
 
 	<-start
 
-	mgr.Schedule("t2", 1*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("t2", 1*time.Millisecond, func(ctx context.Context) error {
 		close(done)
+		return nil
 	})
 
 	select {
@@ -99,19 +135,20 @@ func TestManager_StrategyBlockCancelWhileWaiting(t *testing.T) {
 	releaseFirst := make(chan struct{})
 	secondRan := make(chan struct{})
 
-	mgr.Schedule("t1", 1*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("t1", 1*time.Millisecond, func(ctx context.Context) error {
 		close(firstRunning)
 		<-releaseFirst
+		return nil
 	})
 	<-firstRunning
 
 	// This task should wait for capacity and must not run if canceled while waiting.
-	mgr.Schedule("t2", 0, func(ctx context.Context) {
+	mgr.Schedule("t2", 0, func(ctx context.Context) error {
 		close(secondRan)
+		return nil
 	})
 
-	// Give t2 time to reach the blocking acquire path.
-	time.Sleep(20 * time.Millisecond)
+	waitForWaiters(t, mgr, 1)
 	mgr.Cancel("t2")
 	close(releaseFirst)
 
@@ -126,9 +163,10 @@ func TestManager_ShutdownTimeout(t *testing.T) {
 	mgr := NewManager()
 	start := make(chan struct{})
 
-	mgr.Schedule("stubborn-task", 1*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("stubborn-task", 1*time.Millisecond, func(ctx context.Context) error {
 		close(start)
 		time.Sleep(100 * time.Millisecond)
+		return nil
 	})
 
 	<-start
@@ -144,25 +182,31 @@ func TestManager_ShutdownTimeout(t *testing.T) {
 
 func TestManager_RescheduleKeepsNewestEntry(t *testing.T) {
 	spy := &spyLogger{}
-	mgr := NewManager(WithLogger(spy))
+	clock := newFakeClock(time.Now())
+	mgr := NewManager(WithLogger(spy), WithClock(clock))
 
 	started := make(chan struct{})
 	release := make(chan struct{})
 	secondRan := make(chan struct{})
 
-	mgr.Schedule("same-id", 1*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("same-id", time.Millisecond, func(ctx context.Context) error {
 		close(started)
 		<-release
+		return nil
 	})
-
+	clock.Advance(time.Millisecond)
 	<-started
 
-	mgr.Schedule("same-id", 100*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("same-id", time.Hour, func(ctx context.Context) error {
 		close(secondRan)
+		return nil
 	})
 
 	close(release)
 
+	// The replacement is armed an hour out on the fake clock, so the clock
+	// not having moved is itself the proof it can't have fired yet; this
+	// sleep only guards against a goroutine scheduling surprise.
 	select {
 	case <-secondRan:
 		t.Fatal("second task ran too early")
@@ -170,11 +214,12 @@ func TestManager_RescheduleKeepsNewestEntry(t *testing.T) {
 	}
 
 	mgr.Cancel("same-id")
+	clock.Advance(time.Hour)
 
 	select {
 	case <-secondRan:
 		t.Fatal("cancel should prevent the newest task from running")
-	case <-time.After(150 * time.Millisecond):
+	case <-time.After(20 * time.Millisecond):
 	}
 }
 
@@ -182,9 +227,10 @@ func TestManager_ShutdownCanRetryAfterTimeout(t *testing.T) {
 	mgr := NewManager()
 	started := make(chan struct{})
 
-	mgr.Schedule("retry", 1*time.Millisecond, func(ctx context.Context) {
+	mgr.Schedule("retry", 1*time.Millisecond, func(ctx context.Context) error {
 		close(started)
 		time.Sleep(60 * time.Millisecond)
+		return nil
 	})
 
 	<-started
@@ -210,8 +256,9 @@ func TestManager_ScheduleAfterShutdownIsNoOp(t *testing.T) {
 	}
 
 	ran := make(chan struct{}, 1)
-	mgr.Schedule("late-task", 0, func(ctx context.Context) {
+	mgr.Schedule("late-task", 0, func(ctx context.Context) error {
 		ran <- struct{}{}
+		return nil
 	})
 
 	select {
@@ -238,7 +285,7 @@ func TestCoverageBooster(t *testing.T) {
 func TestCoverage_TimerRaceGuard(t *testing.T) {
 	mgr := NewManager()
 
-	mgr.Schedule("race-trigger", 0, func(ctx context.Context) {})
+	mgr.Schedule("race-trigger", 0, func(ctx context.Context) error { return nil })
 
 	mgr.mu.Lock()
 	mgr.isClosed = true
@@ -250,11 +297,729 @@ func TestCoverage_TimerRaceGuard(t *testing.T) {
 func TestManager_ManualCancel(t *testing.T) {
 	mgr := NewManager()
 
-	mgr.Schedule("cancel-me", 1*time.Hour, func(ctx context.Context) {
+	mgr.Schedule("cancel-me", 1*time.Hour, func(ctx context.Context) error {
 		t.Error("this task should have been cancelled and never run")
+		return nil
 	})
 
 	mgr.Cancel("cancel-me")
 
 	time.Sleep(10 * time.Millisecond)
 }
+
+func TestManager_RetriesOnFailure(t *testing.T) {
+	mgr := NewManager()
+
+	var mu sync.Mutex
+	attempts := 0
+	done := make(chan struct{})
+
+	mgr.Schedule("flaky", 1*time.Millisecond, func(ctx context.Context) error {
+		mu.Lock()
+		attempts++
+		n := attempts
+		mu.Unlock()
+
+		if n < 3 {
+			return fmt.Errorf("attempt %d failed", n)
+		}
+		close(done)
+		return nil
+	}, WithMaxRetries(5), WithRetryDelayFunc(func(attempt int, err error) time.Duration {
+		return time.Millisecond
+	}))
+
+	select {
+	case <-done:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("task never succeeded after retries")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestManager_RetriesExhausted(t *testing.T) {
+	var attempts int32
+	var handlerAttempt int
+	handlerCalled := make(chan struct{})
+
+	mgr := NewManager(WithErrorHandler(func(id string, err error, attempt int) {
+		handlerAttempt = attempt
+		close(handlerCalled)
+	}))
+
+	mgr.Schedule("always-fails", 1*time.Millisecond, func(ctx context.Context) error {
+		attempts++
+		return errors.New("boom")
+	}, WithMaxRetries(2))
+
+	select {
+	case <-handlerCalled:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("error handler was never invoked")
+	}
+
+	if handlerAttempt != 3 {
+		t.Fatalf("expected error handler to fire on attempt 3, got %d", handlerAttempt)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts (1 initial + 2 retries), got %d", attempts)
+	}
+}
+
+func TestManager_TaskTimeout(t *testing.T) {
+	mgr := NewManager()
+
+	errCh := make(chan error, 1)
+	mgr.Schedule("slow-task", 1*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		errCh <- ctx.Err()
+		return ctx.Err()
+	}, WithTimeout(10*time.Millisecond))
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("task did not observe its timeout")
+	}
+}
+
+func TestManager_CancelStopsRetryBackoff(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	mgr := NewManager(WithClock(clock))
+
+	secondAttempt := make(chan struct{})
+	mgr.Schedule("cancel-during-backoff", time.Millisecond, func(ctx context.Context) error {
+		select {
+		case <-secondAttempt:
+			t.Error("task should not have retried after being cancelled")
+		default:
+			close(secondAttempt)
+		}
+		return errors.New("fail once")
+	}, WithMaxRetries(3), WithRetryDelayFunc(func(attempt int, err error) time.Duration {
+		return 50 * time.Millisecond
+	}))
+
+	clock.Advance(time.Millisecond)
+	<-secondAttempt
+	mgr.Cancel("cancel-during-backoff")
+
+	// The retry is armed 50ms out on the fake clock; advancing well past
+	// that proves Cancel stopped it deterministically, rather than the
+	// backoff merely not having elapsed yet in real time.
+	clock.Advance(time.Hour)
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestManager_PriorityAdmissionPreemptsFIFO(t *testing.T) {
+	mgr := NewManager(WithLimit(1, StrategyBlock))
+
+	holding := make(chan struct{})
+	release := make(chan struct{})
+	mgr.Schedule("holder", 0, func(ctx context.Context) error {
+		close(holding)
+		<-release
+		return nil
+	})
+	<-holding
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) Task {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	lowDone := make(chan struct{})
+	highDone := make(chan struct{})
+
+	mgr.Schedule("low", 0, func(ctx context.Context) error {
+		err := record("low")(ctx)
+		close(lowDone)
+		return err
+	}, WithPriority(1))
+
+	// Wait for "low" to actually join the wait queue before "high" enqueues.
+	waitForWaiters(t, mgr, 1)
+
+	mgr.Schedule("high", 0, func(ctx context.Context) error {
+		err := record("high")(ctx)
+		close(highDone)
+		return err
+	}, WithPriority(10))
+
+	waitForWaiters(t, mgr, 2)
+	close(release)
+
+	for _, done := range []chan struct{}{highDone, lowDone} {
+		select {
+		case <-done:
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("task did not run")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 2 || order[0] != "high" {
+		t.Fatalf("expected high priority task to run first, got %v", order)
+	}
+}
+
+func TestManager_PriorityLevelsBucketing(t *testing.T) {
+	mgr := NewManager(WithLimit(1, StrategyBlock), WithPriorityLevels(4))
+
+	holding := make(chan struct{})
+	release := make(chan struct{})
+	mgr.Schedule("holder", 0, func(ctx context.Context) error {
+		close(holding)
+		<-release
+		return nil
+	})
+	<-holding
+
+	var mu sync.Mutex
+	var order []string
+
+	lowDone := make(chan struct{})
+	highDone := make(chan struct{})
+
+	mgr.Schedule("low", 0, func(ctx context.Context) error {
+		mu.Lock()
+		order = append(order, "low")
+		mu.Unlock()
+		close(lowDone)
+		return nil
+	}, WithPriority(1))
+
+	waitForWaiters(t, mgr, 1)
+
+	mgr.Schedule("high", 0, func(ctx context.Context) error {
+		mu.Lock()
+		order = append(order, "high")
+		mu.Unlock()
+		close(highDone)
+		return nil
+	}, WithPriority(99)) // clamped into the top bucket
+
+	waitForWaiters(t, mgr, 2)
+	close(release)
+
+	for _, done := range []chan struct{}{highDone, lowDone} {
+		select {
+		case <-done:
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("task did not run")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != 2 || order[0] != "high" {
+		t.Fatalf("expected high priority task to run first, got %v", order)
+	}
+}
+
+func TestManager_PriorityWaiterCancelDoesNotLeakSlot(t *testing.T) {
+	mgr := NewManager(WithLimit(1, StrategyBlock))
+
+	holding := make(chan struct{})
+	release := make(chan struct{})
+	mgr.Schedule("holder", 0, func(ctx context.Context) error {
+		close(holding)
+		<-release
+		return nil
+	})
+	<-holding
+
+	ran := make(chan struct{}, 1)
+	mgr.Schedule("waiter", 0, func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}, WithPriority(5))
+
+	waitForWaiters(t, mgr, 1)
+	mgr.Cancel("waiter")
+	close(release)
+
+	select {
+	case <-ran:
+		t.Fatal("canceled waiter should not have run")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The slot must have been returned to the pool, not leaked.
+	next := make(chan struct{})
+	mgr.Schedule("after", 0, func(ctx context.Context) error {
+		close(next)
+		return nil
+	})
+
+	select {
+	case <-next:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("slot appears to have leaked after cancelling a waiter")
+	}
+}
+
+// stubClock wraps the real clock but counts AfterFunc calls, so tests can
+// assert that scheduling goes through an injected Clock rather than calling
+// time.AfterFunc directly.
+type stubClock struct {
+	mu             sync.Mutex
+	afterFuncCalls int
+}
+
+func (c *stubClock) Now() time.Time { return time.Now() }
+
+func (c *stubClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	c.afterFuncCalls++
+	c.mu.Unlock()
+	return time.AfterFunc(d, f)
+}
+
+// fakeClock is a manual Clock for tests that need deterministic control over
+// scheduling, retry backoff, and recurrence timing instead of real delays.
+// It is the internal counterpart to pendingtest.ManualClock; that one can't
+// be imported here, since pendingtest itself imports this package.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, fireAt: c.now.Add(d), f: f, active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, then synchronously runs the
+// callback of every timer whose deadline is now due, earliest first. A
+// callback that registers a further timer (as a retry or a recurrence rearm
+// does) is itself picked up by the same Advance call if its deadline also
+// falls at or before the new time.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	target := c.now
+	c.mu.Unlock()
+
+	for {
+		due := c.popDue(target)
+		if due == nil {
+			return
+		}
+		due.f()
+	}
+}
+
+func (c *fakeClock) popDue(target time.Time) *fakeTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var due *fakeTimer
+	for _, t := range c.timers {
+		if !t.active || t.fireAt.After(target) {
+			continue
+		}
+		if due == nil || t.fireAt.Before(due.fireAt) {
+			due = t
+		}
+	}
+	if due != nil {
+		due.active = false
+	}
+	return due
+}
+
+type fakeTimer struct {
+	clock  *fakeClock
+	fireAt time.Time
+	f      func()
+	active bool
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasActive := t.active
+	t.active = false
+	return wasActive
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasActive := t.active
+	t.fireAt = t.clock.now.Add(d)
+	t.active = true
+	return wasActive
+}
+
+func TestManager_WithClockUsesProvidedClock(t *testing.T) {
+	clock := &stubClock{}
+	mgr := NewManager(WithClock(clock))
+
+	done := make(chan struct{})
+	mgr.Schedule("task", 0, func(ctx context.Context) error {
+		close(done)
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("task did not run")
+	}
+
+	clock.mu.Lock()
+	calls := clock.afterFuncCalls
+	clock.mu.Unlock()
+	if calls == 0 {
+		t.Fatal("expected Schedule to go through the provided clock's AfterFunc")
+	}
+}
+
+type tenantIDKey struct{}
+
+func TestManager_BaseContextSeedsTaskContext(t *testing.T) {
+	mgr := NewManager(WithBaseContext(func() context.Context {
+		return context.WithValue(context.Background(), tenantIDKey{}, "acme")
+	}))
+
+	done := make(chan struct{})
+	var tenant any
+	mgr.Schedule("task", 0, func(ctx context.Context) error {
+		tenant = ctx.Value(tenantIDKey{})
+		close(done)
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("task did not run")
+	}
+
+	if tenant != "acme" {
+		t.Fatalf("expected tenant id from base context, got %v", tenant)
+	}
+}
+
+func TestManager_BaseContextCancellationCancelsTask(t *testing.T) {
+	baseCtx, baseCancel := context.WithCancel(context.Background())
+	mgr := NewManager(WithBaseContext(func() context.Context { return baseCtx }))
+
+	started := make(chan struct{})
+	errCh := make(chan error, 1)
+	mgr.Schedule("task", 0, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		errCh <- ctx.Err()
+		return ctx.Err()
+	})
+
+	<-started
+	baseCancel()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("task context was not cancelled by the base context")
+	}
+}
+
+func TestManager_WithTaskLoggerDecoratesID(t *testing.T) {
+	spy := &spyLogger{}
+	mgr := NewManager(WithLogger(spy))
+
+	done := make(chan struct{})
+	mgr.Schedule("task", 0, func(ctx context.Context) error {
+		WithTaskLogger(ctx).OnFailed("ignored", errors.New("reported via task logger"))
+		close(done)
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("task did not run")
+	}
+
+	spy.mu.Lock()
+	defer spy.mu.Unlock()
+	if len(spy.failedIDs) != 1 || spy.failedIDs[0] != "task" {
+		t.Fatalf("expected WithTaskLogger to report under the task's own id, got %v", spy.failedIDs)
+	}
+}
+
+func TestManager_PendingReportsScheduledTask(t *testing.T) {
+	mgr := NewManager()
+	mgr.Schedule("task", time.Hour, func(ctx context.Context) error { return nil }, WithPriority(3))
+
+	infos := mgr.Pending()
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 pending task, got %d", len(infos))
+	}
+	info := infos[0]
+	if info.ID != "task" {
+		t.Fatalf("expected id %q, got %q", "task", info.ID)
+	}
+	if info.State != StatePending {
+		t.Fatalf("expected StatePending, got %v", info.State)
+	}
+	if info.Attempt != 1 {
+		t.Fatalf("expected attempt 1, got %d", info.Attempt)
+	}
+	if info.Priority != 3 {
+		t.Fatalf("expected priority 3, got %v", info.Priority)
+	}
+	if !info.FireAt.After(info.ScheduledAt) {
+		t.Fatalf("expected FireAt after ScheduledAt, got fireAt=%v scheduledAt=%v", info.FireAt, info.ScheduledAt)
+	}
+
+	if len(mgr.Running()) != 0 {
+		t.Fatalf("expected no running tasks, got %d", len(mgr.Running()))
+	}
+	if stats := mgr.Stats(); stats.PendingCount != 1 || stats.RunningCount != 0 {
+		t.Fatalf("expected PendingCount 1 and RunningCount 0, got %+v", stats)
+	}
+
+	mgr.Cancel("task")
+	if len(mgr.Pending()) != 0 {
+		t.Fatal("expected Cancel to remove the task from Pending")
+	}
+	if stats := mgr.Stats(); stats.PendingCount != 0 {
+		t.Fatalf("expected PendingCount 0 after cancel, got %d", stats.PendingCount)
+	}
+}
+
+func TestManager_RunningReportsExecutingTask(t *testing.T) {
+	mgr := NewManager()
+
+	inTask := make(chan struct{})
+	release := make(chan struct{})
+	mgr.Schedule("task", 0, func(ctx context.Context) error {
+		close(inTask)
+		<-release
+		return nil
+	})
+
+	<-inTask
+	infos := mgr.Running()
+	if len(infos) != 1 || infos[0].ID != "task" || infos[0].State != StateRunning {
+		t.Fatalf("expected task reported as running, got %+v", infos)
+	}
+	if len(mgr.Pending()) != 0 {
+		t.Fatalf("expected Pending to exclude the running task, got %d", len(mgr.Pending()))
+	}
+	if stats := mgr.Stats(); stats.RunningCount != 1 || stats.PendingCount != 0 {
+		t.Fatalf("expected RunningCount 1 and PendingCount 0, got %+v", stats)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if mgr.Stats().RunningCount == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("expected RunningCount to drop to 0 once the task returned")
+}
+
+func TestManager_CancelWhileRunningKeepsStatsConsistentWithRunning(t *testing.T) {
+	mgr := NewManager()
+
+	inTask := make(chan struct{})
+	mgr.Schedule("task", 0, func(ctx context.Context) error {
+		close(inTask)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	<-inTask
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && mgr.Stats().RunningCount != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	mgr.Cancel("task")
+
+	if len(mgr.Running()) != 0 {
+		t.Fatalf("expected Running to drop the cancelled task immediately, got %v", mgr.Running())
+	}
+	if got := mgr.Stats().RunningCount; got != 0 {
+		t.Fatalf("expected RunningCount to drop to 0 as soon as Cancel removes the task, got %d", got)
+	}
+}
+
+// TestManager_ConcurrentScheduleCancelNeverCorruptsPendingCount drives
+// Schedule and Cancel on the same id back-to-back from many goroutines. A
+// Cancel landing between markWaiting and markRunning inside an in-flight
+// runAttempt must never cause a second decrement of pendingGauge; if it did,
+// PendingCount would drift, including going negative.
+func TestManager_ConcurrentScheduleCancelNeverCorruptsPendingCount(t *testing.T) {
+	mgr := NewManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				mgr.Schedule("t", 0, func(ctx context.Context) error { return nil })
+				mgr.Cancel("t")
+			}
+		}()
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if len(mgr.Pending()) == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := mgr.Stats().PendingCount; got < 0 {
+		t.Fatalf("expected PendingCount to never go negative, got %d", got)
+	}
+}
+
+func TestManager_StatsTracksOutcomeCounters(t *testing.T) {
+	mgr := NewManager()
+
+	done := make(chan struct{})
+	attempts := 0
+	mgr.Schedule("flaky", 0, func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("boom")
+		}
+		close(done)
+		return nil
+	}, WithMaxRetries(1), WithRetryDelayFunc(func(attempt int, err error) time.Duration { return 0 }))
+
+	select {
+	case <-done:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("task never succeeded after retry")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		stats := mgr.Stats()
+		if stats.ExecutedTotal == 1 && stats.RetriedTotal == 1 {
+			if stats.AvgLatency < 0 {
+				t.Fatalf("expected non-negative AvgLatency, got %v", stats.AvgLatency)
+			}
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected ExecutedTotal 1 and RetriedTotal 1, got %+v", mgr.Stats())
+}
+
+func TestManager_StatsTracksDroppedTotal(t *testing.T) {
+	mgr := NewManager(WithLimit(1, StrategyDrop))
+
+	release := make(chan struct{})
+	mgr.Schedule("holder", 0, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && mgr.Stats().RunningCount != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if mgr.Stats().RunningCount != 1 {
+		t.Fatal("timed out waiting for holder task to start running")
+	}
+
+	done := make(chan struct{})
+	mgr.Schedule("dropped", time.Millisecond, func(ctx context.Context) error {
+		close(done)
+		return nil
+	})
+
+	dropDeadline := time.Now().Add(time.Second)
+	for time.Now().Before(dropDeadline) {
+		if mgr.Stats().DroppedTotal == 1 {
+			close(release)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	t.Fatalf("expected DroppedTotal 1, got %+v", mgr.Stats())
+}
+
+type fakeRegisterer struct {
+	gauges   map[string]func() float64
+	counters map[string]func() float64
+}
+
+func (r *fakeRegisterer) RegisterGauge(name string, value func() float64) {
+	if r.gauges == nil {
+		r.gauges = make(map[string]func() float64)
+	}
+	r.gauges[name] = value
+}
+
+func (r *fakeRegisterer) RegisterCounter(name string, value func() float64) {
+	if r.counters == nil {
+		r.counters = make(map[string]func() float64)
+	}
+	r.counters[name] = value
+}
+
+func TestManager_WithMetricsRegistererExposesCounters(t *testing.T) {
+	reg := &fakeRegisterer{}
+	mgr := NewManager(WithMetricsRegisterer(reg))
+
+	mgr.Schedule("task", time.Hour, func(ctx context.Context) error { return nil })
+
+	pending, ok := reg.gauges["pending_tasks"]
+	if !ok {
+		t.Fatal("expected pending_tasks gauge to be registered")
+	}
+	if got := pending(); got != 1 {
+		t.Fatalf("expected pending_tasks gauge to report 1, got %v", got)
+	}
+
+	if _, ok := reg.counters["executed_tasks_total"]; !ok {
+		t.Fatal("expected executed_tasks_total counter to be registered")
+	}
+}
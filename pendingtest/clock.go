@@ -0,0 +1,109 @@
+// Package pendingtest provides a pending.Clock for deterministic tests.
+package pendingtest
+
+import (
+	"sync"
+	"time"
+
+	"kahoon/pending"
+)
+
+// ManualClock is a pending.Clock whose time only moves when Advance is
+// called. Tests use it with pending.WithClock to drive a Manager's
+// scheduling, retry backoff, and recurrence deterministically instead of
+// relying on time.Sleep and wall-clock timers.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// NewManualClock returns a ManualClock starting at now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the clock's current time, as last set by Advance.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc registers f to run once the clock has advanced by at least d.
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) pending.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &manualTimer{clock: c, fireAt: c.now.Add(d), f: f, active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, then synchronously runs the
+// callback of every timer whose deadline is now due, earliest first. A
+// callback that registers a further timer (as retries and recurrence
+// re-arms do) is itself picked up by the same Advance call if its deadline
+// also falls at or before the new time.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	target := c.now
+	c.mu.Unlock()
+
+	for {
+		due := c.popDue(target)
+		if due == nil {
+			return
+		}
+		due.f()
+	}
+}
+
+func (c *ManualClock) popDue(target time.Time) *manualTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var due *manualTimer
+	for _, t := range c.timers {
+		if !t.active || t.fireAt.After(target) {
+			continue
+		}
+		if due == nil || t.fireAt.Before(due.fireAt) {
+			due = t
+		}
+	}
+	if due != nil {
+		due.active = false
+	}
+	return due
+}
+
+type manualTimer struct {
+	clock  *ManualClock
+	fireAt time.Time
+	f      func()
+	active bool
+}
+
+// Stop prevents the timer from firing, as *time.Timer.Stop.
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.active = false
+	return wasActive
+}
+
+// Reset reschedules the timer to fire d after the clock's current time, as
+// *time.Timer.Reset.
+func (t *manualTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	wasActive := t.active
+	t.fireAt = t.clock.now.Add(d)
+	t.active = true
+	return wasActive
+}
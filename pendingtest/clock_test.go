@@ -0,0 +1,117 @@
+package pendingtest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"kahoon/pending"
+)
+
+func TestManualClock_AdvanceFiresDueTimers(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+
+	var fired []string
+	clock.AfterFunc(10*time.Millisecond, func() { fired = append(fired, "first") })
+	clock.AfterFunc(20*time.Millisecond, func() { fired = append(fired, "second") })
+
+	clock.Advance(5 * time.Millisecond)
+	if len(fired) != 0 {
+		t.Fatalf("expected no timers due yet, got %v", fired)
+	}
+
+	clock.Advance(10 * time.Millisecond)
+	if want := []string{"first"}; !equal(fired, want) {
+		t.Fatalf("expected %v, got %v", want, fired)
+	}
+
+	clock.Advance(10 * time.Millisecond)
+	if want := []string{"first", "second"}; !equal(fired, want) {
+		t.Fatalf("expected %v, got %v", want, fired)
+	}
+}
+
+func TestManualClock_StopPreventsFire(t *testing.T) {
+	clock := NewManualClock(time.Now())
+
+	fired := false
+	timer := clock.AfterFunc(time.Millisecond, func() { fired = true })
+	if !timer.Stop() {
+		t.Fatal("expected Stop to report the timer as active")
+	}
+
+	clock.Advance(time.Second)
+	if fired {
+		t.Fatal("stopped timer should not have fired")
+	}
+}
+
+func TestManualClock_ResetReschedules(t *testing.T) {
+	clock := NewManualClock(time.Now())
+
+	fired := false
+	timer := clock.AfterFunc(time.Millisecond, func() { fired = true })
+	timer.Reset(time.Hour)
+
+	clock.Advance(time.Minute)
+	if fired {
+		t.Fatal("timer reset into the future should not have fired yet")
+	}
+
+	clock.Advance(time.Hour)
+	if !fired {
+		t.Fatal("expected timer to fire after its reset deadline")
+	}
+}
+
+func TestManager_ManualClockDrivesScheduleWithoutSleep(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	mgr := pending.NewManager(pending.WithClock(clock))
+
+	var mu sync.Mutex
+	ran := false
+	mgr.Schedule("task", 5*time.Second, func(ctx context.Context) error {
+		mu.Lock()
+		ran = true
+		mu.Unlock()
+		return nil
+	})
+
+	clock.Advance(4 * time.Second)
+	mu.Lock()
+	fired := ran
+	mu.Unlock()
+	if fired {
+		t.Fatal("task fired before its delay elapsed")
+	}
+
+	clock.Advance(1 * time.Second)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		fired = ran
+		mu.Unlock()
+		if fired {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !fired {
+		t.Fatal("expected task to run once the clock reached its delay")
+	}
+}
+
+func equal(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package pending
+
+import "time"
+
+// RecurrencePolicy computes the next time a recurring task should run,
+// given the time it was last considered (typically time.Now() right after
+// the previous run finished).
+type RecurrencePolicy interface {
+	Next(now time.Time) time.Time
+}
+
+// EveryPolicy re-arms a task on a fixed interval. The first tick fires at
+// StartAt, or one Interval after now (now.Add(Interval)) if StartAt is zero.
+type EveryPolicy struct {
+	Interval time.Duration
+	StartAt  time.Time
+}
+
+// Next returns StartAt if it is still in the future, otherwise now+Interval.
+func (p EveryPolicy) Next(now time.Time) time.Time {
+	if !p.StartAt.IsZero() && p.StartAt.After(now) {
+		return p.StartAt
+	}
+	return now.Add(p.Interval)
+}
+
+// DailyAtPolicy fires once a day at OffsetOfDay past midnight, in now's
+// location.
+type DailyAtPolicy struct {
+	OffsetOfDay time.Duration
+}
+
+// Next returns today's occurrence of OffsetOfDay if it is still ahead of
+// now, otherwise tomorrow's.
+func (p DailyAtPolicy) Next(now time.Time) time.Time {
+	next := startOfDay(now).Add(p.OffsetOfDay)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// WeeklyPolicy fires once a week on Weekday at OffsetOfDay past midnight, in
+// now's location.
+type WeeklyPolicy struct {
+	Weekday     time.Weekday
+	OffsetOfDay time.Duration
+}
+
+// Next returns this week's occurrence of Weekday/OffsetOfDay if it is still
+// ahead of now, otherwise next week's.
+func (p WeeklyPolicy) Next(now time.Time) time.Time {
+	next := startOfDay(now).Add(p.OffsetOfDay)
+	daysUntil := (int(p.Weekday) - int(now.Weekday()) + 7) % 7
+	next = next.AddDate(0, 0, daysUntil)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
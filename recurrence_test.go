@@ -0,0 +1,185 @@
+package pending
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEveryPolicy_Next(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	p := EveryPolicy{Interval: time.Hour}
+	if next := p.Next(now); !next.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expected now+interval, got %v", next)
+	}
+
+	future := now.Add(2 * time.Hour)
+	p = EveryPolicy{Interval: time.Hour, StartAt: future}
+	if next := p.Next(now); !next.Equal(future) {
+		t.Fatalf("expected StartAt %v, got %v", future, next)
+	}
+}
+
+func TestDailyAtPolicy_Next(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	p := DailyAtPolicy{OffsetOfDay: 13 * time.Hour}
+	want := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+	if next := p.Next(now); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+
+	p = DailyAtPolicy{OffsetOfDay: 11 * time.Hour}
+	want = time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC)
+	if next := p.Next(now); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+func TestWeeklyPolicy_Next(t *testing.T) {
+	// 2024-01-01 is a Monday.
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	p := WeeklyPolicy{Weekday: time.Wednesday, OffsetOfDay: 9 * time.Hour}
+	want := time.Date(2024, 1, 3, 9, 0, 0, 0, time.UTC)
+	if next := p.Next(now); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+
+	// Requesting today's weekday after the offset has passed rolls to next week.
+	p = WeeklyPolicy{Weekday: time.Monday, OffsetOfDay: 1 * time.Hour}
+	want = time.Date(2024, 1, 8, 1, 0, 0, 0, time.UTC)
+	if next := p.Next(now); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
+
+func TestManager_ScheduleRecurringRunsRepeatedly(t *testing.T) {
+	mgr := NewManager()
+
+	var mu sync.Mutex
+	runs := 0
+	done := make(chan struct{})
+
+	mgr.ScheduleRecurring("heartbeat", EveryPolicy{Interval: 10 * time.Millisecond}, func(ctx context.Context) error {
+		mu.Lock()
+		runs++
+		n := runs
+		mu.Unlock()
+		if n == 3 {
+			close(done)
+		}
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("recurring task did not run 3 times in time")
+	}
+
+	mgr.Cancel("heartbeat")
+}
+
+func TestManager_CancelStopsRecurrence(t *testing.T) {
+	clock := newFakeClock(time.Now())
+	mgr := NewManager(WithClock(clock))
+
+	var mu sync.Mutex
+	runs := 0
+	ran := make(chan struct{}, 1)
+
+	mgr.ScheduleRecurring("ticker", EveryPolicy{Interval: 5 * time.Millisecond}, func(ctx context.Context) error {
+		mu.Lock()
+		runs++
+		mu.Unlock()
+		ran <- struct{}{}
+		return nil
+	})
+
+	// Advance one tick at a time, waiting for each run and its rearm to land
+	// before advancing again, so the fake clock always has the next timer
+	// registered by the time Advance looks for one.
+	for i := 0; i < 3; i++ {
+		clock.Advance(5 * time.Millisecond)
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Fatalf("tick %d never ran", i+1)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			infos := mgr.Pending()
+			if len(infos) == 1 && infos[0].State == StatePending {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	mgr.Cancel("ticker")
+	clock.Advance(time.Hour)
+
+	// Cancel removed the entry, so nothing is registered for Advance to
+	// fire; this only guards against a run already in flight finishing late.
+	time.Sleep(20 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if runs != 3 {
+		t.Fatalf("expected exactly 3 runs before Cancel, got %d", runs)
+	}
+}
+
+func TestManager_ShutdownDoesNotReArmRecurrence(t *testing.T) {
+	mgr := NewManager()
+
+	started := make(chan struct{})
+	mgr.ScheduleRecurring("slow-tick", EveryPolicy{Interval: time.Millisecond}, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := mgr.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	if _, ok := mgr.pending["slow-tick"]; ok {
+		t.Fatal("recurring entry should not survive shutdown")
+	}
+}
+
+func TestManager_RecurrenceSkippedOnOverrun(t *testing.T) {
+	spy := &spyLogger{}
+	mgr := NewManager(WithLogger(spy))
+
+	// The run takes far longer than the interval, so the next tick is missed.
+	done := make(chan struct{})
+	mgr.ScheduleRecurring("overrunning", EveryPolicy{Interval: time.Millisecond}, func(ctx context.Context) error {
+		time.Sleep(30 * time.Millisecond)
+		close(done)
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("task never ran")
+	}
+
+	mgr.Cancel("overrunning")
+
+	spy.mu.Lock()
+	defer spy.mu.Unlock()
+	if !spy.recurrenceSkipped {
+		t.Fatal("expected OnRecurrenceSkipped to fire for the overrun tick")
+	}
+}
@@ -11,6 +11,12 @@ type TelemetryHandler interface {
 	OnExecuted(id string, duration time.Duration)
 	OnCancelled(id string)
 	OnFailed(id string, err error)
+	// OnRetried is called after a failed attempt is re-armed for retry, with
+	// the number of the upcoming attempt (2 for the first retry).
+	OnRetried(id string, attempt int)
+	// OnRecurrenceSkipped is called when a recurring task's run overran its
+	// next scheduled tick, causing that tick to be skipped entirely.
+	OnRecurrenceSkipped(id string, reason string)
 }
 
 type nopLogger struct{}
@@ -20,3 +26,5 @@ func (n nopLogger) OnRescheduled(id string)                 {}
 func (n nopLogger) OnExecuted(id string, dur time.Duration) {}
 func (n nopLogger) OnCancelled(id string)                   {}
 func (n nopLogger) OnFailed(id string, err error)           {}
+func (n nopLogger) OnRetried(id string, attempt int)        {}
+func (n nopLogger) OnRecurrenceSkipped(id, reason string)   {}